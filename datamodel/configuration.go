@@ -0,0 +1,15 @@
+// Copyright 2022 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+// Package datamodel holds configuration shared across the low and high
+// model builders for a single document.
+package datamodel
+
+// Configuration controls how a document is built.
+type Configuration struct {
+
+	// SchemaBuildConcurrency is the number of workers used to build Schema
+	// property and composed-subschema graphs concurrently while this
+	// document is parsed. A value <= 0 defaults to runtime.GOMAXPROCS(0).
+	SchemaBuildConcurrency int
+}
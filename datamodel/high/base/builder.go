@@ -0,0 +1,145 @@
+// Copyright 2022 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package base
+
+// SchemaBuilder constructs a Schema programmatically, without a backing
+// low-model node, for spec-first tooling, test fixture generators and other
+// callers that want to synthesize OpenAPI documents rather than parse them.
+// Every method returns the builder so calls can be chained, finishing with
+// Build.
+type SchemaBuilder struct {
+	s *Schema
+}
+
+// NewSchemaBuilder starts building a new Schema.
+func NewSchemaBuilder() *SchemaBuilder {
+	return &SchemaBuilder{s: &Schema{}}
+}
+
+// Typed sets the schema's type(s), e.g. Typed("string") or, for a 3.1
+// nullable string, Typed("string", "null").
+func (b *SchemaBuilder) Typed(types ...string) *SchemaBuilder {
+	b.s.Type = types
+	return b
+}
+
+// WithTitle sets the schema's title.
+func (b *SchemaBuilder) WithTitle(title string) *SchemaBuilder {
+	b.s.Title = title
+	return b
+}
+
+// WithDescription sets the schema's description.
+func (b *SchemaBuilder) WithDescription(description string) *SchemaBuilder {
+	b.s.Description = description
+	return b
+}
+
+// WithFormat sets the schema's format, e.g. "email" or "date-time".
+func (b *SchemaBuilder) WithFormat(format string) *SchemaBuilder {
+	b.s.Format = format
+	return b
+}
+
+// WithEnum sets the schema's permitted values.
+func (b *SchemaBuilder) WithEnum(values ...string) *SchemaBuilder {
+	b.s.Enum = values
+	return b
+}
+
+// WithDefault sets the schema's default value.
+func (b *SchemaBuilder) WithDefault(value any) *SchemaBuilder {
+	b.s.Default = value
+	return b
+}
+
+// Nullable marks the schema as nullable (OpenAPI 3.0 style).
+func (b *SchemaBuilder) Nullable(nullable bool) *SchemaBuilder {
+	b.s.Nullable = nullable
+	return b
+}
+
+// WithProperty adds a named property to the schema, marking the schema as
+// an object if it isn't typed already.
+func (b *SchemaBuilder) WithProperty(name string, schema *Schema) *SchemaBuilder {
+	if b.s.Properties == nil {
+		b.s.Properties = make(map[string]*SchemaProxy)
+	}
+	b.s.Properties[name] = newProxy(schema)
+	if len(b.s.Type) == 0 {
+		b.s.Type = []string{"object"}
+	}
+	return b
+}
+
+// WithAdditionalProperties sets additionalProperties to either a bool or a
+// *Schema, mirroring the field's own type.
+func (b *SchemaBuilder) WithAdditionalProperties(additionalProperties any) *SchemaBuilder {
+	b.s.AdditionalProperties = additionalProperties
+	return b
+}
+
+// Required marks the given property names as required.
+func (b *SchemaBuilder) Required(names ...string) *SchemaBuilder {
+	b.s.Required = append(b.s.Required, names...)
+	return b
+}
+
+// Items sets the schema's array item schema.
+func (b *SchemaBuilder) Items(schema *Schema) *SchemaBuilder {
+	b.s.Items = []*SchemaProxy{newProxy(schema)}
+	return b
+}
+
+// TupleItems sets a fixed, positional list of item schemas.
+func (b *SchemaBuilder) TupleItems(schemas ...*Schema) *SchemaBuilder {
+	b.s.Items = schemasToProxies(schemas)
+	return b
+}
+
+// AllOf adds schemas to the AllOf composition.
+func (b *SchemaBuilder) AllOf(schemas ...*Schema) *SchemaBuilder {
+	b.s.AllOf = append(b.s.AllOf, schemasToProxies(schemas)...)
+	return b
+}
+
+// OneOf adds schemas to the OneOf composition.
+func (b *SchemaBuilder) OneOf(schemas ...*Schema) *SchemaBuilder {
+	b.s.OneOf = append(b.s.OneOf, schemasToProxies(schemas)...)
+	return b
+}
+
+// AnyOf adds schemas to the AnyOf composition.
+func (b *SchemaBuilder) AnyOf(schemas ...*Schema) *SchemaBuilder {
+	b.s.AnyOf = append(b.s.AnyOf, schemasToProxies(schemas)...)
+	return b
+}
+
+// Not adds schemas to the Not composition.
+func (b *SchemaBuilder) Not(schemas ...*Schema) *SchemaBuilder {
+	b.s.Not = append(b.s.Not, schemasToProxies(schemas)...)
+	return b
+}
+
+// WithExtension adds a single x- extension to the schema.
+func (b *SchemaBuilder) WithExtension(name string, value any) *SchemaBuilder {
+	if b.s.Extensions == nil {
+		b.s.Extensions = make(map[string]any)
+	}
+	b.s.Extensions[name] = value
+	return b
+}
+
+// Build finishes the builder and returns the constructed Schema.
+func (b *SchemaBuilder) Build() *Schema {
+	return b.s
+}
+
+func schemasToProxies(schemas []*Schema) []*SchemaProxy {
+	proxies := make([]*SchemaProxy, len(schemas))
+	for i, s := range schemas {
+		proxies[i] = newProxy(s)
+	}
+	return proxies
+}
@@ -0,0 +1,102 @@
+// Copyright 2022 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"text/template"
+)
+
+var funcMap = template.FuncMap{
+	"title": exportedName,
+}
+
+var fileTmpl = template.Must(template.New("file").Funcs(funcMap).Parse(`// Code generated by libopenapi gen. DO NOT EDIT.
+
+package {{ .PackageName }}
+
+{{ range .Decls }}
+{{- template "decl" . }}
+{{ end }}
+`))
+
+func init() {
+	template.Must(fileTmpl.New("decl").Parse(`
+{{- if .Doc }}// {{ .Name }} {{ .Doc }}
+{{ end -}}
+{{- if eq .Kind 0 }}
+type {{ .Name }} struct {
+{{- range .Fields }}
+	{{ .Name }} {{ .GoType }} ` + "`json:\"{{ .JSONName }}{{ if and (not .Required) (ne .JSONName \"-\") }},omitempty{{ end }}\"`" + `
+{{- end }}
+}
+{{- else if eq .Kind 1 }}
+type {{ .Name }} {{ .Underlying }}
+
+const (
+{{- range .Variants }}
+	{{ $.Name }}{{ . | title }} {{ $.Name }} = {{ printf "%q" . }}
+{{- end }}
+)
+{{- else if eq .Kind 4 }}
+// {{ .Name }} is a tuple: its elements are positional, not named.
+type {{ .Name }} struct {
+{{- range $i, $e := .TupleElems }}
+	Elem{{ $i }} {{ $e }}
+{{- end }}
+}
+{{- else if eq .Kind 5 }}
+// {{ .Name }} is satisfied by every oneOf/anyOf variant below.
+{{- if .Discriminator }}
+// Discriminated on {{ .Discriminator.PropertyName }}.
+{{- end }}
+type {{ .Name }} interface {
+	is{{ .Name }}()
+}
+{{- range .Variants }}
+func (*{{ . }}) is{{ $.Name }}() {}
+{{- end }}
+{{- else if or (eq .Kind 2) (eq .Kind 3) (eq .Kind 6) }}
+type {{ .Name }} {{ .Underlying }}
+{{- end }}
+`))
+}
+
+func emit(decls []typeDecl, opts GenOpts) ([]GeneratedFile, error) {
+	buckets := make(map[string][]typeDecl)
+	for _, d := range decls {
+		file := opts.PackageName + ".go"
+		if opts.FileForType != nil {
+			if f := opts.FileForType(d.Name); f != "" {
+				file = f
+			}
+		}
+		buckets[file] = append(buckets[file], d)
+	}
+
+	files := make([]GeneratedFile, 0, len(buckets))
+	for name, fileDecls := range buckets {
+		var buf bytes.Buffer
+		t, err := fileTmpl.Clone()
+		if err != nil {
+			return nil, err
+		}
+		t.Funcs(funcMap)
+		if err := t.Execute(&buf, struct {
+			PackageName string
+			Decls       []typeDecl
+		}{opts.PackageName, fileDecls}); err != nil {
+			return nil, fmt.Errorf("gen: rendering %q: %w", name, err)
+		}
+
+		formatted, err := format.Source(buf.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("gen: formatting %q: %w", name, err)
+		}
+		files = append(files, GeneratedFile{Name: name, Source: formatted})
+	}
+	return files, nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2022 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+// Package gen renders idiomatic Go type declarations from high.Schema graphs,
+// so libopenapi can be used as a model generator without pulling in the
+// go-swagger toolchain.
+package gen
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+)
+
+// GenOpts controls how Generate renders Go source from a set of schemas.
+type GenOpts struct {
+
+	// PackageName is the package clause written at the top of every GeneratedFile.
+	PackageName string
+
+	// FileForType, when set, is used to decide which output file a named type is
+	// written to. It is called with the type's generated name. If it returns "",
+	// the type is written to a single file named after PackageName.
+	FileForType func(typeName string) string
+}
+
+// GeneratedFile is a single rendered Go source file, ready to be written to disk.
+type GeneratedFile struct {
+	Name   string
+	Source []byte
+}
+
+// Generate walks every schema in schemas and emits idiomatic Go type
+// declarations for them: structs for object schemas, named types for string
+// enums, slice types for arrays, map[string]T for pure additionalProperties,
+// tuple structs for prefixItems-style fixed arrays, and interface types for
+// oneOf/anyOf with tag-based discrimination when Discriminator is set.
+//
+// Anonymous inline object schemas are flattened into named sibling types
+// before anything is emitted, so the resulting Go code never contains
+// anonymous structs.
+func Generate(schemas map[string]*base.Schema, opts GenOpts) ([]GeneratedFile, error) {
+	if opts.PackageName == "" {
+		return nil, fmt.Errorf("gen: PackageName must be set")
+	}
+
+	names := make([]string, 0, len(schemas))
+	for name := range schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	b := newModelBuilder()
+	for _, name := range names {
+		if schemas[name] == nil {
+			return nil, fmt.Errorf("gen: schema %q is nil", name)
+		}
+		if _, err := b.buildNamed(name, schemas[name]); err != nil {
+			return nil, fmt.Errorf("gen: building %q: %w", name, err)
+		}
+	}
+
+	return emit(b.decls, opts)
+}
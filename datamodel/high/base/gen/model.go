@@ -0,0 +1,377 @@
+// Copyright 2022 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package gen
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+)
+
+// typeKind identifies the shape of a rendered Go type declaration.
+type typeKind int
+
+const (
+	kindStruct typeKind = iota
+	kindEnum
+	kindSlice
+	kindMap
+	kindTuple
+	kindInterface
+	kindAlias
+)
+
+// fieldDecl is a single struct field, rendered from an object property.
+type fieldDecl struct {
+	Name     string
+	JSONName string
+	GoType   string
+	Required bool
+}
+
+// typeDecl is the internal, flattened representation of one generated Go
+// type. Anonymous inline schemas are lifted out into their own typeDecl by
+// the builder before emission ever sees them.
+type typeDecl struct {
+	Name             string
+	Kind             typeKind
+	Doc              string
+	Fields           []fieldDecl
+	Underlying       string   // element/alias type for kindSlice, kindMap, kindEnum, kindAlias
+	TupleElems       []string // element types for kindTuple, in positional order
+	Variants         []string // implementor type names for kindInterface (oneOf/anyOf)
+	Discriminator    *base.Discriminator
+	HasExtensionProp bool // true when the object also carries additionalProperties
+}
+
+// modelBuilder flattens a Schema graph into a flat slice of typeDecl, naming
+// every anonymous inline object schema along the way.
+type modelBuilder struct {
+	decls []typeDecl
+	names map[string]bool
+}
+
+func newModelBuilder() *modelBuilder {
+	return &modelBuilder{names: make(map[string]bool)}
+}
+
+// buildNamed registers schema under name, flattening any anonymous children
+// it contains, and returns the Go type name to use when referencing it. The
+// schema at the top of the schemas map always gets its own declaration, even
+// when it's a bare array, map or primitive, so every entry Generate is given
+// ends up with a usable declared type.
+func (b *modelBuilder) buildNamed(name string, schema *base.Schema) (string, error) {
+	return b.build(name, schema, true)
+}
+
+// build renders schema into zero or more typeDecl entries (schema itself
+// plus any anonymous children it flattens out) and returns the Go type
+// expression a field or element referencing schema should use. topLevel is
+// true for the schema passed directly to buildNamed and for each oneOf/anyOf
+// variant (see buildPolymorphic); other nested calls (a property, an array
+// element, a map value) pass false, since those are inlined as Go type
+// expressions rather than given their own declaration. Each kind-specific builder is responsible for reserving its
+// own name via uniqueName exactly once.
+func (b *modelBuilder) build(proposedName string, schema *base.Schema, topLevel bool) (string, error) {
+	switch {
+	case isTuple(schema):
+		return b.buildTuple(proposedName, schema)
+	case isPolymorphic(schema):
+		return b.buildPolymorphic(proposedName, schema)
+	case isEnum(schema):
+		return b.buildEnum(proposedName, schema)
+	case isArray(schema):
+		return b.buildArray(proposedName, schema, topLevel)
+	case isPureMap(schema):
+		return b.buildMap(proposedName, schema, topLevel)
+	case isObject(schema):
+		return b.buildStruct(proposedName, schema)
+	case topLevel:
+		return b.buildAlias(proposedName, schema)
+	default:
+		return goPrimitive(schema), nil
+	}
+}
+
+func (b *modelBuilder) buildStruct(name string, schema *base.Schema) (string, error) {
+	name = b.uniqueName(name)
+	required := make(map[string]bool, len(schema.Required))
+	for _, r := range schema.Required {
+		required[r] = true
+	}
+
+	propNames := make([]string, 0, len(schema.Properties))
+	for p := range schema.Properties {
+		propNames = append(propNames, p)
+	}
+	sort.Strings(propNames)
+
+	decl := typeDecl{Name: name, Kind: kindStruct, Doc: schema.Description}
+
+	for _, p := range propNames {
+		proxy := schema.Properties[p]
+		child := resolveProxy(proxy)
+		if child == nil {
+			return "", fmt.Errorf("property %q.%s has no resolvable schema", name, p)
+		}
+		childName := name + exportedName(p)
+		goType, err := b.build(childName, child, false)
+		if err != nil {
+			return "", err
+		}
+		decl.Fields = append(decl.Fields, fieldDecl{
+			Name:     exportedName(p),
+			JSONName: p,
+			GoType:   goType,
+			Required: required[p],
+		})
+	}
+
+	// properties + additionalProperties is rewritten into a struct with an
+	// escape-hatch map field, rather than a pure map[string]T.
+	extensionType := ""
+	switch ap := schema.AdditionalProperties.(type) {
+	case bool:
+		if ap {
+			extensionType = "map[string]any"
+		}
+	case *base.Schema:
+		valueType, err := b.build(name+"Value", ap, false)
+		if err != nil {
+			return "", err
+		}
+		extensionType = "map[string]" + valueType
+	}
+	if extensionType != "" && len(schema.Properties) > 0 {
+		decl.HasExtensionProp = true
+		decl.Fields = append(decl.Fields, fieldDecl{
+			Name:     "AdditionalProperties",
+			JSONName: "-",
+			GoType:   extensionType,
+			Required: false,
+		})
+	}
+
+	b.decls = append(b.decls, decl)
+	return "*" + name, nil
+}
+
+func (b *modelBuilder) buildEnum(name string, schema *base.Schema) (string, error) {
+	name = b.uniqueName(name)
+	b.decls = append(b.decls, typeDecl{
+		Name:       name,
+		Kind:       kindEnum,
+		Doc:        schema.Description,
+		Underlying: "string",
+		Variants:   append([]string(nil), schema.Enum...),
+	})
+	return name, nil
+}
+
+func (b *modelBuilder) buildArray(name string, schema *base.Schema, topLevel bool) (string, error) {
+	elemType := "any"
+	if len(schema.Items) == 1 {
+		if elemSchema := resolveProxy(schema.Items[0]); elemSchema != nil {
+			var err error
+			elemType, err = b.build(name+"Item", elemSchema, false)
+			if err != nil {
+				return "", err
+			}
+		}
+	}
+	goType := "[]" + elemType
+
+	if !topLevel {
+		return goType, nil
+	}
+	name = b.uniqueName(name)
+	b.decls = append(b.decls, typeDecl{Name: name, Kind: kindSlice, Doc: schema.Description, Underlying: goType})
+	return name, nil
+}
+
+func (b *modelBuilder) buildTuple(name string, schema *base.Schema) (string, error) {
+	name = b.uniqueName(name)
+	decl := typeDecl{Name: name, Kind: kindTuple, Doc: schema.Description}
+	for i, item := range schema.Items {
+		child := resolveProxy(item)
+		if child == nil {
+			return "", fmt.Errorf("tuple %q element %d has no resolvable schema", name, i)
+		}
+		elemType, err := b.build(fmt.Sprintf("%sElem%d", name, i), child, false)
+		if err != nil {
+			return "", err
+		}
+		decl.TupleElems = append(decl.TupleElems, elemType)
+	}
+	b.decls = append(b.decls, decl)
+	return "*" + name, nil
+}
+
+func (b *modelBuilder) buildMap(name string, schema *base.Schema, topLevel bool) (string, error) {
+	valueType := "any"
+	if valueSchema, ok := schema.AdditionalProperties.(*base.Schema); ok {
+		var err error
+		valueType, err = b.build(name+"Value", valueSchema, false)
+		if err != nil {
+			return "", err
+		}
+	}
+	goType := "map[string]" + valueType
+
+	if !topLevel {
+		return goType, nil
+	}
+	name = b.uniqueName(name)
+	b.decls = append(b.decls, typeDecl{Name: name, Kind: kindMap, Doc: schema.Description, Underlying: goType})
+	return name, nil
+}
+
+// buildAlias handles a top-level schema that is neither an object, array,
+// map, enum, tuple nor composite: a bare primitive still gets its own named
+// declaration so every entry in the schemas map Generate was given produces
+// usable Go source.
+func (b *modelBuilder) buildAlias(name string, schema *base.Schema) (string, error) {
+	name = b.uniqueName(name)
+	b.decls = append(b.decls, typeDecl{Name: name, Kind: kindAlias, Doc: schema.Description, Underlying: goPrimitive(schema)})
+	return name, nil
+}
+
+func (b *modelBuilder) buildPolymorphic(name string, schema *base.Schema) (string, error) {
+	name = b.uniqueName(name)
+	decl := typeDecl{Name: name, Kind: kindInterface, Doc: schema.Description, Discriminator: schema.Discriminator}
+
+	composites := schema.OneOf
+	if len(composites) == 0 {
+		composites = schema.AnyOf
+	}
+	for i, proxy := range composites {
+		child := resolveProxy(proxy)
+		if child == nil {
+			continue
+		}
+		variantName := fmt.Sprintf("%sVariant%d", name, i)
+		if child.Title != "" {
+			variantName = name + exportedName(child.Title)
+		}
+		// topLevel is true here, not false like every other nested call: the
+		// emitted interface satisfaction method is func (*T) is{{Name}}(), which
+		// requires a named declared type. A variant that resolves to a bare
+		// array, map or primitive has no such type unless build is told to
+		// give it its own declaration, the same way buildNamed does for the
+		// schema passed directly to Generate.
+		goType, err := b.build(variantName, child, true)
+		if err != nil {
+			return "", err
+		}
+		decl.Variants = append(decl.Variants, strings.TrimPrefix(goType, "*"))
+	}
+
+	b.decls = append(b.decls, decl)
+	return name, nil
+}
+
+func (b *modelBuilder) uniqueName(name string) string {
+	name = exportedName(name)
+	if !b.names[name] {
+		b.names[name] = true
+		return name
+	}
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s%d", name, i)
+		if !b.names[candidate] {
+			b.names[candidate] = true
+			return candidate
+		}
+	}
+}
+
+// resolveProxy dereferences a SchemaProxy to the Schema it points at,
+// following $ref lazily via the proxy's own Schema() accessor.
+func resolveProxy(proxy *base.SchemaProxy) *base.Schema {
+	if proxy == nil {
+		return nil
+	}
+	return proxy.Schema()
+}
+
+func isObject(s *base.Schema) bool {
+	return hasType(s, "object") && len(s.Properties) > 0
+}
+
+func isPureMap(s *base.Schema) bool {
+	if !hasType(s, "object") || len(s.Properties) > 0 {
+		return false
+	}
+	_, ok := s.AdditionalProperties.(*base.Schema)
+	return ok
+}
+
+func isArray(s *base.Schema) bool {
+	return hasType(s, "array") && len(s.Items) <= 1
+}
+
+// isTuple detects a fixed-length positional array, the prefixItems idiom:
+// more than one entry under Items means a tuple rather than a homogeneous list.
+func isTuple(s *base.Schema) bool {
+	return hasType(s, "array") && len(s.Items) > 1
+}
+
+func isEnum(s *base.Schema) bool {
+	return len(s.Enum) > 0 && hasType(s, "string")
+}
+
+func isPolymorphic(s *base.Schema) bool {
+	return len(s.OneOf) > 0 || len(s.AnyOf) > 0
+}
+
+func hasType(s *base.Schema, t string) bool {
+	for _, st := range s.Type {
+		if st == t {
+			return true
+		}
+	}
+	return false
+}
+
+func goPrimitive(s *base.Schema) string {
+	switch {
+	case hasType(s, "string") && s.Format == "date-time":
+		return "time.Time"
+	case hasType(s, "string") && s.Format == "byte":
+		return "[]byte"
+	case hasType(s, "string"):
+		return "string"
+	case hasType(s, "integer") && s.Format == "int32":
+		return "int32"
+	case hasType(s, "integer"):
+		return "int64"
+	case hasType(s, "number") && s.Format == "float":
+		return "float32"
+	case hasType(s, "number"):
+		return "float64"
+	case hasType(s, "boolean"):
+		return "bool"
+	default:
+		return "any"
+	}
+}
+
+// exportedName turns an arbitrary schema/property name into an exported Go
+// identifier, splitting on the usual JSON-name separators.
+func exportedName(name string) string {
+	fields := strings.FieldsFunc(name, func(r rune) bool {
+		return r == '_' || r == '-' || r == '.' || r == ' '
+	})
+	var sb strings.Builder
+	for _, f := range fields {
+		sb.WriteString(strings.ToUpper(f[:1]))
+		sb.WriteString(f[1:])
+	}
+	if sb.Len() == 0 {
+		return "Field"
+	}
+	return sb.String()
+}
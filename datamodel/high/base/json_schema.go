@@ -0,0 +1,405 @@
+// Copyright 2022 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package base
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// JSONSchemaDraft selects which JSON Schema dialect RenderJSONSchema targets.
+type JSONSchemaDraft int
+
+const (
+	// Draft202012 renders https://json-schema.org/draft/2020-12/schema.
+	Draft202012 JSONSchemaDraft = iota
+
+	// Draft07 renders http://json-schema.org/draft-07/schema#.
+	Draft07
+)
+
+const (
+	draft202012URI = "https://json-schema.org/draft/2020-12/schema"
+	draft07URI     = "http://json-schema.org/draft-07/schema#"
+)
+
+// RenderJSONSchema emits a self-contained JSON Schema document for s,
+// translating the OpenAPI-isms in Schema into their JSON Schema equivalents
+// for draft. Every named schema reachable from s (one with a Title) is
+// collected into $defs (draft 2020-12) or definitions (draft-07) and
+// referenced by $ref, so the result validates on its own without the
+// surrounding OpenAPI document. A schema reachable from itself, titled or
+// not, is likewise broken into a $ref rather than expanded forever; see
+// jsonSchemaNode.
+func (s *Schema) RenderJSONSchema(draft JSONSchemaDraft) ([]byte, error) {
+	if s == nil {
+		return nil, fmt.Errorf("cannot render a nil schema")
+	}
+
+	ctx := &jsonSchemaCtx{
+		defs:       make(map[string]any),
+		path:       make(map[*Schema]string),
+		referenced: make(map[string]bool),
+	}
+	root := jsonSchemaNode(s, draft, ctx, true)
+
+	switch draft {
+	case Draft202012:
+		root["$schema"] = draft202012URI
+		if len(ctx.defs) > 0 {
+			root["$defs"] = ctx.defs
+		}
+	case Draft07:
+		root["$schema"] = draft07URI
+		if len(ctx.defs) > 0 {
+			root["definitions"] = ctx.defs
+		}
+	default:
+		return nil, fmt.Errorf("unknown JSON Schema draft: %d", draft)
+	}
+
+	return json.MarshalIndent(root, "", "  ")
+}
+
+// jsonSchemaCtx carries the state threaded through a single RenderJSONSchema
+// call. path records every schema currently being built on the path from the
+// root, by the $ref string a cyclic reference back to it should use, so a
+// schema reachable from itself - directly, or through a chain of other
+// schemas - is broken into a $ref instead of recursing forever. referenced
+// tracks which of those path entries were actually used for a cycle, since an
+// anonymous schema that turns out not to be self-referential should stay
+// inlined rather than cluttering defs with an entry nothing points to.
+type jsonSchemaCtx struct {
+	defs       map[string]any
+	path       map[*Schema]string
+	referenced map[string]bool
+	anonSeq    int
+}
+
+// jsonSchemaNode renders s as a JSON Schema node. A non-root schema with a
+// Title is hoisted into defs (once) and referenced by $ref, so reused
+// component schemas are emitted a single time. Every schema, titled or not,
+// is also registered in ctx.path before its body is built: if building that
+// body recurses back into s (a cycle), the recursive call finds s already on
+// the path and emits a $ref instead of recursing again. The root schema
+// refs back to itself as "#"; a non-root, untitled schema that turns out to
+// be cyclic is hoisted into defs under a synthesized name, lazily, only once
+// a cycle has actually been found.
+func jsonSchemaNode(s *Schema, draft JSONSchemaDraft, ctx *jsonSchemaCtx, isRoot bool) map[string]any {
+	if name, onPath := ctx.path[s]; onPath {
+		ctx.referenced[name] = true
+		if name == "#" {
+			return map[string]any{"$ref": "#"}
+		}
+		return map[string]any{"$ref": refPath(draft, name)}
+	}
+
+	if !isRoot && s.Title != "" {
+		if _, exists := ctx.defs[s.Title]; !exists {
+			ctx.path[s] = s.Title
+			ctx.defs[s.Title] = buildJSONSchemaNode(s, draft, ctx)
+			delete(ctx.path, s)
+		}
+		return map[string]any{"$ref": refPath(draft, s.Title)}
+	}
+
+	name := "#"
+	if !isRoot {
+		ctx.anonSeq++
+		name = fmt.Sprintf("schema%d", ctx.anonSeq)
+	}
+	ctx.path[s] = name
+	node := buildJSONSchemaNode(s, draft, ctx)
+	delete(ctx.path, s)
+
+	if !isRoot && ctx.referenced[name] {
+		ctx.defs[name] = node
+		return map[string]any{"$ref": refPath(draft, name)}
+	}
+	return node
+}
+
+func buildJSONSchemaNode(s *Schema, draft JSONSchemaDraft, ctx *jsonSchemaCtx) map[string]any {
+	node := make(map[string]any)
+
+	nullableOneOf := writeType(node, s, draft)
+	writeExclusiveBounds(node, s, draft)
+
+	if s.Title != "" {
+		node["title"] = s.Title
+	}
+	if s.Description != "" {
+		node["description"] = s.Description
+	}
+	if s.Pattern != "" {
+		node["pattern"] = s.Pattern
+	}
+	if s.Format != "" {
+		node["format"] = s.Format
+	}
+	if s.MultipleOf != 0 {
+		node["multipleOf"] = s.MultipleOf
+	}
+	if s.MaxLength != 0 {
+		node["maxLength"] = s.MaxLength
+	}
+	if s.MinLength != 0 {
+		node["minLength"] = s.MinLength
+	}
+	if s.MaxItems != 0 {
+		node["maxItems"] = s.MaxItems
+	}
+	if s.MinItems != 0 {
+		node["minItems"] = s.MinItems
+	}
+	if s.MaxProperties != 0 {
+		node["maxProperties"] = s.MaxProperties
+	}
+	if s.MinProperties != 0 {
+		node["minProperties"] = s.MinProperties
+	}
+	if len(s.Required) > 0 {
+		node["required"] = s.Required
+	}
+	if len(s.Enum) > 0 {
+		node["enum"] = s.Enum
+	}
+	if s.ReadOnly {
+		node["readOnly"] = true
+	}
+	if s.WriteOnly {
+		node["writeOnly"] = true
+	}
+	if s.Default != nil {
+		node["default"] = s.Default
+	}
+
+	if examples := collectExamples(s); len(examples) > 0 {
+		node["examples"] = examples
+	}
+
+	if s.Discriminator != nil {
+		node["$comment"] = fmt.Sprintf("discriminator: %s", s.Discriminator.PropertyName)
+	}
+
+	if len(s.Properties) > 0 {
+		props := make(map[string]any, len(s.Properties))
+		for name, proxy := range s.Properties {
+			if child := proxy.Schema(); child != nil {
+				props[name] = jsonSchemaNode(child, draft, ctx, false)
+			}
+		}
+		node["properties"] = props
+	}
+
+	if s.AdditionalProperties != nil {
+		switch ap := s.AdditionalProperties.(type) {
+		case bool:
+			node["additionalProperties"] = ap
+		case *Schema:
+			node["additionalProperties"] = jsonSchemaNode(ap, draft, ctx, false)
+		}
+	}
+
+	writeItems(node, s, draft, ctx)
+	writeComposition(node, "allOf", s.AllOf, draft, ctx)
+	writeComposition(node, "oneOf", s.OneOf, draft, ctx)
+	writeComposition(node, "anyOf", s.AnyOf, draft, ctx)
+	writeComposition(node, "not", s.Not, draft, ctx)
+
+	applyNullableOneOf(node, nullableOneOf)
+
+	return node
+}
+
+// writeType renders the type keyword, folding OpenAPI's nullable flag into
+// the JSON Schema representation for draft: a "null" member of the type
+// union for 2020-12, or a "oneOf" wrapper around the type and a "null" type
+// for draft-07. For the draft-07 case it returns that wrapper's members
+// instead of writing them to node directly, since the schema's own oneOf
+// composition (written later by writeComposition) would otherwise clobber
+// it; applyNullableOneOf merges the two once both are known.
+func writeType(node map[string]any, s *Schema, draft JSONSchemaDraft) []any {
+	if len(s.Type) == 0 {
+		return nil
+	}
+	if !s.Nullable {
+		if len(s.Type) == 1 {
+			node["type"] = s.Type[0]
+		} else {
+			node["type"] = s.Type
+		}
+		return nil
+	}
+
+	switch draft {
+	case Draft202012:
+		node["type"] = append(append([]string{}, s.Type...), "null")
+	case Draft07:
+		var typeVal any = s.Type[0]
+		if len(s.Type) > 1 {
+			typeVal = s.Type
+		}
+		return []any{
+			map[string]any{"type": typeVal},
+			map[string]any{"type": "null"},
+		}
+	}
+	return nil
+}
+
+// applyNullableOneOf merges a draft-07 nullable type wrapper (from writeType)
+// into node. If node has no oneOf of its own the wrapper is written as-is;
+// otherwise both are preserved by folding them into an allOf, since writing
+// either straight to "oneOf" would silently discard the other.
+func applyNullableOneOf(node map[string]any, nullableOneOf []any) {
+	if nullableOneOf == nil {
+		return
+	}
+	existingOneOf, hasOneOf := node["oneOf"]
+	if !hasOneOf {
+		node["oneOf"] = nullableOneOf
+		return
+	}
+	delete(node, "oneOf")
+	wrapped := []any{
+		map[string]any{"oneOf": nullableOneOf},
+		map[string]any{"oneOf": existingOneOf},
+	}
+	if existingAllOf, ok := node["allOf"]; ok {
+		node["allOf"] = append(existingAllOf.([]any), wrapped...)
+	} else {
+		node["allOf"] = wrapped
+	}
+}
+
+// writeExclusiveBounds translates between the OpenAPI 3.0 boolean
+// exclusiveMinimum/exclusiveMaximum (paired with minimum/maximum) and the
+// JSON Schema 2020-12 standalone numeric form. Presence of each bound is
+// read from the low-model node rather than a zero check against s.Minimum/
+// s.Maximum/s.ExclusiveMinimum/s.ExclusiveMaximum, since 0 is itself a
+// legitimate bound and would otherwise be indistinguishable from absent.
+func writeExclusiveBounds(node map[string]any, s *Schema, draft JSONSchemaDraft) {
+	hasMin, hasMax, hasExclusiveMin, hasExclusiveMax := boundsPresence(s)
+
+	switch draft {
+	case Draft202012:
+		switch {
+		case s.ExclusiveMinimumBool:
+			node["exclusiveMinimum"] = s.Minimum
+		case hasExclusiveMin:
+			node["exclusiveMinimum"] = s.ExclusiveMinimum
+		case hasMin:
+			node["minimum"] = s.Minimum
+		}
+		switch {
+		case s.ExclusiveMaximumBool:
+			node["exclusiveMaximum"] = s.Maximum
+		case hasExclusiveMax:
+			node["exclusiveMaximum"] = s.ExclusiveMaximum
+		case hasMax:
+			node["maximum"] = s.Maximum
+		}
+	case Draft07:
+		switch {
+		case hasExclusiveMin:
+			node["minimum"] = s.ExclusiveMinimum
+			node["exclusiveMinimum"] = true
+		case s.ExclusiveMinimumBool:
+			node["minimum"] = s.Minimum
+			node["exclusiveMinimum"] = true
+		case hasMin:
+			node["minimum"] = s.Minimum
+		}
+		switch {
+		case hasExclusiveMax:
+			node["maximum"] = s.ExclusiveMaximum
+			node["exclusiveMaximum"] = true
+		case s.ExclusiveMaximumBool:
+			node["maximum"] = s.Maximum
+			node["exclusiveMaximum"] = true
+		case hasMax:
+			node["maximum"] = s.Maximum
+		}
+	}
+}
+
+// boundsPresence reports whether s's minimum/maximum and numeric (3.1-style)
+// exclusiveMinimum/exclusiveMaximum were actually set on the source document,
+// consulting the low-model node when one is available. A Schema with no low
+// model (built with SchemaBuilder, which has no way to set these fields yet)
+// falls back to a zero check.
+func boundsPresence(s *Schema) (hasMin, hasMax, hasExclusiveMin, hasExclusiveMax bool) {
+	low := s.GoLow()
+	if low == nil {
+		return s.Minimum != 0, s.Maximum != 0, s.ExclusiveMinimum != 0, s.ExclusiveMaximum != 0
+	}
+	hasMin = !low.Minimum.IsEmpty()
+	hasMax = !low.Maximum.IsEmpty()
+	hasExclusiveMin = !low.ExclusiveMinimum.IsEmpty() && low.ExclusiveMinimum.Value.IsB()
+	hasExclusiveMax = !low.ExclusiveMaximum.IsEmpty() && low.ExclusiveMaximum.Value.IsB()
+	return hasMin, hasMax, hasExclusiveMin, hasExclusiveMax
+}
+
+// writeItems renders Items, choosing between a single schema applied to
+// every element, and the prefixItems/positional-items tuple form depending
+// on draft.
+func writeItems(node map[string]any, s *Schema, draft JSONSchemaDraft, ctx *jsonSchemaCtx) {
+	switch len(s.Items) {
+	case 0:
+		return
+	case 1:
+		if child := s.Items[0].Schema(); child != nil {
+			node["items"] = jsonSchemaNode(child, draft, ctx, false)
+		}
+	default:
+		elems := make([]any, 0, len(s.Items))
+		for _, proxy := range s.Items {
+			if child := proxy.Schema(); child != nil {
+				elems = append(elems, jsonSchemaNode(child, draft, ctx, false))
+			}
+		}
+		switch draft {
+		case Draft202012:
+			node["prefixItems"] = elems
+		case Draft07:
+			node["items"] = elems
+		}
+	}
+}
+
+func writeComposition(node map[string]any, keyword string, members []*SchemaProxy, draft JSONSchemaDraft, ctx *jsonSchemaCtx) {
+	if len(members) == 0 {
+		return
+	}
+	rendered := make([]any, 0, len(members))
+	for _, proxy := range members {
+		if child := proxy.Schema(); child != nil {
+			rendered = append(rendered, jsonSchemaNode(child, draft, ctx, false))
+		}
+	}
+	if keyword == "not" {
+		if len(rendered) > 0 {
+			node[keyword] = rendered[0]
+		}
+		return
+	}
+	node[keyword] = rendered
+}
+
+func collectExamples(s *Schema) []any {
+	var examples []any
+	if s.Example != nil {
+		examples = append(examples, s.Example)
+	}
+	examples = append(examples, s.Examples...)
+	return examples
+}
+
+func refPath(draft JSONSchemaDraft, name string) string {
+	if draft == Draft07 {
+		return "#/definitions/" + name
+	}
+	return "#/$defs/" + name
+}
@@ -0,0 +1,217 @@
+// Copyright 2022 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package base
+
+// NormalizeOptions controls which rewrites Normalize applies. Every option
+// defaults to off so callers opt into exactly the canonicalization they need.
+type NormalizeOptions struct {
+
+	// FlattenInlineObjects names anonymous inline object schemas (those with
+	// no Title) found in Properties and Items, assigning each a generated
+	// Title derived from its parent's Title and its property name (or "Item"
+	// for an array element). Each named schema stays where it is, inline in
+	// its parent; it is not moved out into a separate sibling schema.
+	FlattenInlineObjects bool
+
+	// CollapseAllOf merges non-nullable AllOf compositions into the schema
+	// that declares them, unioning Required, Properties and the common
+	// constraint fields, and clearing AllOf once merged.
+	CollapseAllOf bool
+
+	// RewriteAdditionalProperties makes the additionalProperties behavior of
+	// every object schema explicit: an object with Properties and no
+	// AdditionalProperties set is treated as closed (false), while an object
+	// with no Properties and no AdditionalProperties set is treated as a free
+	// form map (true).
+	RewriteAdditionalProperties bool
+}
+
+// Normalize applies the canonical rewrites selected by opts to s and every
+// schema it reaches through Properties, Items, AllOf, OneOf, AnyOf and Not,
+// mutating the tree in place and returning s for convenience.
+//
+// Normalize never touches the low-model back-reference (GoLow), so
+// diagnostics that point at the original document keep working before and
+// after a normalization pass. Each rewrite only acts on schemas that haven't
+// already been rewritten, so running Normalize more than once over the same
+// tree is a no-op the second time.
+func Normalize(s *Schema, opts NormalizeOptions) *Schema {
+	nameHint := ""
+	if s != nil {
+		nameHint = s.Title
+	}
+	return normalize(s, opts, nameHint, make(map[*Schema]bool))
+}
+
+func normalize(s *Schema, opts NormalizeOptions, nameHint string, seen map[*Schema]bool) *Schema {
+	if s == nil || seen[s] {
+		return s
+	}
+	seen[s] = true
+
+	if opts.CollapseAllOf {
+		collapseAllOf(s)
+	}
+	if opts.RewriteAdditionalProperties {
+		rewriteAdditionalProperties(s)
+	}
+
+	for name, proxy := range s.Properties {
+		child := proxy.Schema()
+		if child == nil {
+			continue
+		}
+		if opts.FlattenInlineObjects && isAnonymousObject(child) {
+			child.Title = nameHint + exportedTitle(name)
+		}
+		normalize(child, opts, child.Title, seen)
+	}
+
+	for i, items := range [][]*SchemaProxy{s.AllOf, s.OneOf, s.AnyOf, s.Not, s.Items} {
+		for _, proxy := range items {
+			child := proxy.Schema()
+			if child == nil {
+				continue
+			}
+			if i == 4 && opts.FlattenInlineObjects && isAnonymousObject(child) {
+				child.Title = nameHint + "Item"
+			}
+			normalize(child, opts, child.Title, seen)
+		}
+	}
+
+	return s
+}
+
+// collapseAllOf merges every non-nullable AllOf member directly into s and
+// clears AllOf. A schema that is itself Nullable, or whose AllOf contains a
+// further composition (OneOf/AnyOf), is left untouched so the polymorphism
+// it expresses isn't silently lost. Each member is collapsed first, so a
+// chain of nested AllOf compositions flattens all the way down to s in a
+// single Normalize pass.
+func collapseAllOf(s *Schema) {
+	if s.Nullable || len(s.AllOf) == 0 {
+		return
+	}
+
+	required := make(map[string]bool, len(s.Required))
+	for _, r := range s.Required {
+		required[r] = true
+	}
+
+	for _, proxy := range s.AllOf {
+		member := proxy.Schema()
+		if member == nil || len(member.OneOf) > 0 || len(member.AnyOf) > 0 {
+			return
+		}
+		collapseAllOf(member)
+		if len(member.AllOf) > 0 {
+			return
+		}
+	}
+
+	for _, proxy := range s.AllOf {
+		member := proxy.Schema()
+		if member == nil {
+			continue
+		}
+
+		if s.Properties == nil {
+			s.Properties = make(map[string]*SchemaProxy)
+		}
+		for name, prop := range member.Properties {
+			if _, exists := s.Properties[name]; !exists {
+				s.Properties[name] = prop
+			}
+		}
+		for _, r := range member.Required {
+			if !required[r] {
+				required[r] = true
+				s.Required = append(s.Required, r)
+			}
+		}
+		if len(member.Type) > 0 && len(s.Type) == 0 {
+			s.Type = member.Type
+		}
+		if len(member.Enum) > 0 && len(s.Enum) == 0 {
+			s.Enum = member.Enum
+		}
+		if member.Format != "" && s.Format == "" {
+			s.Format = member.Format
+		}
+		if member.Pattern != "" && s.Pattern == "" {
+			s.Pattern = member.Pattern
+		}
+		if member.MultipleOf != 0 && s.MultipleOf == 0 {
+			s.MultipleOf = member.MultipleOf
+		}
+		if member.MaxLength != 0 && s.MaxLength == 0 {
+			s.MaxLength = member.MaxLength
+		}
+		if member.MinLength != 0 && s.MinLength == 0 {
+			s.MinLength = member.MinLength
+		}
+		if member.MaxItems != 0 && s.MaxItems == 0 {
+			s.MaxItems = member.MaxItems
+		}
+		if member.MinItems != 0 && s.MinItems == 0 {
+			s.MinItems = member.MinItems
+		}
+		if member.MaxProperties != 0 && s.MaxProperties == 0 {
+			s.MaxProperties = member.MaxProperties
+		}
+		if member.MinProperties != 0 && s.MinProperties == 0 {
+			s.MinProperties = member.MinProperties
+		}
+
+		if (member.Minimum != 0 || member.ExclusiveMinimum != 0 || member.ExclusiveMinimumBool) &&
+			s.Minimum == 0 && s.ExclusiveMinimum == 0 && !s.ExclusiveMinimumBool {
+			s.Minimum = member.Minimum
+			s.ExclusiveMinimum = member.ExclusiveMinimum
+			s.ExclusiveMinimumBool = member.ExclusiveMinimumBool
+		}
+		if (member.Maximum != 0 || member.ExclusiveMaximum != 0 || member.ExclusiveMaximumBool) &&
+			s.Maximum == 0 && s.ExclusiveMaximum == 0 && !s.ExclusiveMaximumBool {
+			s.Maximum = member.Maximum
+			s.ExclusiveMaximum = member.ExclusiveMaximum
+			s.ExclusiveMaximumBool = member.ExclusiveMaximumBool
+		}
+	}
+
+	s.AllOf = nil
+}
+
+// rewriteAdditionalProperties makes additionalProperties explicit for every
+// object schema that left it unset, so downstream consumers never have to
+// special-case "unset" as a third state alongside true and false.
+func rewriteAdditionalProperties(s *Schema) {
+	if !hasType(s, "object") || s.AdditionalProperties != nil {
+		return
+	}
+	s.AdditionalProperties = len(s.Properties) == 0
+}
+
+func isAnonymousObject(s *Schema) bool {
+	return s.Title == "" && hasType(s, "object")
+}
+
+func hasType(s *Schema, t string) bool {
+	for _, st := range s.Type {
+		if st == t {
+			return true
+		}
+	}
+	return false
+}
+
+func exportedTitle(name string) string {
+	if name == "" {
+		return name
+	}
+	r := []rune(name)
+	if r[0] >= 'a' && r[0] <= 'z' {
+		r[0] = r[0] - ('a' - 'A')
+	}
+	return string(r)
+}
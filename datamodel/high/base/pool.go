@@ -0,0 +1,64 @@
+// Copyright 2022 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package base
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/pb33f/libopenapi/datamodel"
+)
+
+// SchemaBuildPool is a small bounded worker pool that NewSchemaWithPool
+// submits its property and composed-subschema construction tasks to, so
+// building a document with thousands of properties spawns a handful of
+// goroutines instead of one per property. A pool is scoped to a single
+// document build: callers that share one across every NewSchemaWithPool
+// call for that document must Close it once the document is done with.
+type SchemaBuildPool struct {
+	tasks     chan func()
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewSchemaBuildPool starts a pool sized by config.SchemaBuildConcurrency,
+// defaulting to runtime.GOMAXPROCS(0) when that's <= 0.
+func NewSchemaBuildPool(config datamodel.Configuration) *SchemaBuildPool {
+	workers := config.SchemaBuildConcurrency
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	p := &SchemaBuildPool{tasks: make(chan func()), done: make(chan struct{})}
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for task := range p.tasks {
+				task()
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(p.done)
+	}()
+	return p
+}
+
+// submit schedules task to run on the pool, blocking until a worker is free
+// to pick it up. Callers that need to wait for a batch of submitted tasks to
+// finish should wrap each task in its own sync.WaitGroup.Done call.
+func (p *SchemaBuildPool) submit(task func()) {
+	p.tasks <- task
+}
+
+// Close stops the pool from accepting further work and blocks until every
+// worker goroutine it started has exited. Submitting after Close panics, the
+// same as sending on a closed channel.
+func (p *SchemaBuildPool) Close() {
+	p.closeOnce.Do(func() { close(p.tasks) })
+	<-p.done
+}
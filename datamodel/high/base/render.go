@@ -0,0 +1,281 @@
+// Copyright 2022 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package base
+
+import (
+	"encoding/json"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SchemaVersion selects which OpenAPI schema dialect Render and RenderYAML
+// target, since the two diverge on how nullability and exclusive bounds are
+// expressed.
+type SchemaVersion int
+
+const (
+	// OpenAPI30 renders the OpenAPI 3.0 schema object: a single Type value,
+	// a standalone `nullable` flag, and boolean exclusiveMinimum/exclusiveMaximum.
+	OpenAPI30 SchemaVersion = iota
+
+	// OpenAPI31 renders the OpenAPI 3.1 schema object, which is JSON Schema
+	// 2020-12: Type may hold multiple values (including "null"), and
+	// exclusiveMinimum/exclusiveMaximum are numeric.
+	OpenAPI31
+)
+
+// Render serializes s to OpenAPI-flavored JSON for the given version. Unlike
+// NewSchema, Render works even when s has no backing low model, so a Schema
+// built with SchemaBuilder round-trips without ever having been parsed.
+func (s *Schema) Render(version SchemaVersion) ([]byte, error) {
+	return json.MarshalIndent(renderSchemaNode(s, version, make(map[*Schema]bool)), "", "  ")
+}
+
+// RenderYAML serializes s to OpenAPI-flavored YAML for the given version.
+func (s *Schema) RenderYAML(version SchemaVersion) ([]byte, error) {
+	return yaml.Marshal(renderSchemaNode(s, version, make(map[*Schema]bool)))
+}
+
+// renderSchemaNode renders s, tracking every schema currently being rendered
+// on the path from the root in seen so a self-referential schema (a
+// property or item that, directly or through a chain of other schemas,
+// points back at an ancestor) stops recursing instead of overflowing the
+// stack. seen is scoped to the current path, not the whole tree, so a
+// schema referenced twice from unrelated branches still renders in full
+// both times.
+func renderSchemaNode(s *Schema, version SchemaVersion, seen map[*Schema]bool) map[string]any {
+	if seen[s] {
+		if s.Title != "" {
+			return map[string]any{"title": s.Title}
+		}
+		return map[string]any{}
+	}
+	seen[s] = true
+	defer delete(seen, s)
+
+	node := make(map[string]any)
+
+	writeOpenAPIType(node, s, version)
+	writeExclusiveBounds(node, s, exclusiveDraftFor(version))
+
+	if s.Title != "" {
+		node["title"] = s.Title
+	}
+	if s.Description != "" {
+		node["description"] = s.Description
+	}
+	if s.Format != "" {
+		node["format"] = s.Format
+	}
+	if s.Pattern != "" {
+		node["pattern"] = s.Pattern
+	}
+	if s.MultipleOf != 0 {
+		node["multipleOf"] = s.MultipleOf
+	}
+	if s.MaxLength != 0 {
+		node["maxLength"] = s.MaxLength
+	}
+	if s.MinLength != 0 {
+		node["minLength"] = s.MinLength
+	}
+	if s.MaxItems != 0 {
+		node["maxItems"] = s.MaxItems
+	}
+	if s.MinItems != 0 {
+		node["minItems"] = s.MinItems
+	}
+	if s.MaxProperties != 0 {
+		node["maxProperties"] = s.MaxProperties
+	}
+	if s.MinProperties != 0 {
+		node["minProperties"] = s.MinProperties
+	}
+	if s.UniqueItems != 0 {
+		node["uniqueItems"] = true
+	}
+	if len(s.Required) > 0 {
+		node["required"] = s.Required
+	}
+	if len(s.Enum) > 0 {
+		node["enum"] = s.Enum
+	}
+	if s.Default != nil {
+		node["default"] = s.Default
+	}
+	if s.ReadOnly {
+		node["readOnly"] = true
+	}
+	if s.WriteOnly {
+		node["writeOnly"] = true
+	}
+	if s.Deprecated {
+		node["deprecated"] = true
+	}
+
+	writeExamples(node, s, version)
+
+	if s.Discriminator != nil {
+		discriminator := map[string]any{"propertyName": s.Discriminator.PropertyName}
+		if len(s.Discriminator.Mapping) > 0 {
+			discriminator["mapping"] = s.Discriminator.Mapping
+		}
+		node["discriminator"] = discriminator
+	}
+
+	if s.XML != nil {
+		xml := map[string]any{}
+		if s.XML.Name != "" {
+			xml["name"] = s.XML.Name
+		}
+		if s.XML.Namespace != "" {
+			xml["namespace"] = s.XML.Namespace
+		}
+		if s.XML.Prefix != "" {
+			xml["prefix"] = s.XML.Prefix
+		}
+		if s.XML.Attribute {
+			xml["attribute"] = true
+		}
+		if s.XML.Wrapped {
+			xml["wrapped"] = true
+		}
+		node["xml"] = xml
+	}
+
+	if s.ExternalDocs != nil {
+		externalDocs := map[string]any{}
+		if s.ExternalDocs.Description != "" {
+			externalDocs["description"] = s.ExternalDocs.Description
+		}
+		if s.ExternalDocs.URL != "" {
+			externalDocs["url"] = s.ExternalDocs.URL
+		}
+		node["externalDocs"] = externalDocs
+	}
+
+	if len(s.Properties) > 0 {
+		props := make(map[string]any, len(s.Properties))
+		for name, proxy := range s.Properties {
+			if child := proxy.Schema(); child != nil {
+				props[name] = renderSchemaNode(child, version, seen)
+			}
+		}
+		node["properties"] = props
+	}
+
+	if s.AdditionalProperties != nil {
+		switch ap := s.AdditionalProperties.(type) {
+		case bool:
+			node["additionalProperties"] = ap
+		case *Schema:
+			node["additionalProperties"] = renderSchemaNode(ap, version, seen)
+		}
+	}
+
+	writeRenderedItems(node, s, version, seen)
+	writeRenderedComposition(node, "allOf", s.AllOf, version, seen)
+	writeRenderedComposition(node, "oneOf", s.OneOf, version, seen)
+	writeRenderedComposition(node, "anyOf", s.AnyOf, version, seen)
+	writeRenderedComposition(node, "not", s.Not, version, seen)
+
+	for name, value := range s.Extensions {
+		node[name] = value
+	}
+
+	return node
+}
+
+// writeOpenAPIType renders the type keyword for version: a single value plus
+// a standalone nullable flag for 3.0, or a type union with "null" folded in
+// for 3.1.
+func writeOpenAPIType(node map[string]any, s *Schema, version SchemaVersion) {
+	if len(s.Type) == 0 {
+		return
+	}
+
+	switch version {
+	case OpenAPI30:
+		node["type"] = s.Type[0]
+		if s.Nullable {
+			node["nullable"] = true
+		}
+	case OpenAPI31:
+		if s.Nullable {
+			node["type"] = append(append([]string{}, s.Type...), "null")
+			return
+		}
+		if len(s.Type) == 1 {
+			node["type"] = s.Type[0]
+		} else {
+			node["type"] = s.Type
+		}
+	}
+}
+
+func writeExamples(node map[string]any, s *Schema, version SchemaVersion) {
+	switch version {
+	case OpenAPI30:
+		if s.Example != nil {
+			node["example"] = s.Example
+		}
+	case OpenAPI31:
+		examples := collectExamples(s)
+		if len(examples) > 0 {
+			node["examples"] = examples
+		}
+	}
+}
+
+func writeRenderedItems(node map[string]any, s *Schema, version SchemaVersion, seen map[*Schema]bool) {
+	switch len(s.Items) {
+	case 0:
+		return
+	case 1:
+		if child := s.Items[0].Schema(); child != nil {
+			node["items"] = renderSchemaNode(child, version, seen)
+		}
+	default:
+		elems := make([]any, 0, len(s.Items))
+		for _, proxy := range s.Items {
+			if child := proxy.Schema(); child != nil {
+				elems = append(elems, renderSchemaNode(child, version, seen))
+			}
+		}
+		if version == OpenAPI31 {
+			node["prefixItems"] = elems
+			return
+		}
+		node["items"] = elems
+	}
+}
+
+func writeRenderedComposition(node map[string]any, keyword string, members []*SchemaProxy, version SchemaVersion, seen map[*Schema]bool) {
+	if len(members) == 0 {
+		return
+	}
+	rendered := make([]any, 0, len(members))
+	for _, proxy := range members {
+		if child := proxy.Schema(); child != nil {
+			rendered = append(rendered, renderSchemaNode(child, version, seen))
+		}
+	}
+	if keyword == "not" {
+		if len(rendered) > 0 {
+			node[keyword] = rendered[0]
+		}
+		return
+	}
+	node[keyword] = rendered
+}
+
+// exclusiveDraftFor maps a SchemaVersion onto the JSONSchemaDraft whose
+// exclusiveMinimum/exclusiveMaximum representation it shares, so Render can
+// reuse writeExclusiveBounds instead of duplicating the 3.0/3.1 translation.
+func exclusiveDraftFor(version SchemaVersion) JSONSchemaDraft {
+	if version == OpenAPI31 {
+		return Draft202012
+	}
+	return Draft07
+}
@@ -4,10 +4,12 @@
 package base
 
 import (
+	"sync"
+
+	"github.com/pb33f/libopenapi/datamodel"
 	"github.com/pb33f/libopenapi/datamodel/high"
 	lowmodel "github.com/pb33f/libopenapi/datamodel/low"
 	"github.com/pb33f/libopenapi/datamodel/low/base"
-	"sync"
 )
 
 // Schema represents a
@@ -55,7 +57,47 @@ type Schema struct {
 	low                  *base.Schema
 }
 
+// NewSchema builds schema using a worker pool scoped to this single call,
+// shut down before NewSchema returns. Because the pool doesn't outlive the
+// call, the SchemaProxy values it creates don't keep a reference to it:
+// their lazy, later Schema() calls fall back to spinning up their own
+// ephemeral pool instead of submitting to one that's already closed. A
+// caller building many schemas for the same document (property/composed-
+// subschema resolution is lazy, so that can happen well after the top-level
+// NewSchema call returns) should use NewSchemaWithPool with one pool shared
+// across the whole document instead, so the concurrency ceiling applies
+// document-wide rather than per call.
 func NewSchema(schema *base.Schema) *Schema {
+	return NewSchemaWithConfig(schema, datamodel.Configuration{})
+}
+
+// NewSchemaWithConfig builds schema with a worker pool sized by config,
+// scoped to this single call and shut down before it returns. See NewSchema
+// for why the resulting Schema's proxies don't retain that pool.
+func NewSchemaWithConfig(schema *base.Schema, config datamodel.Configuration) *Schema {
+	pool := NewSchemaBuildPool(config)
+	defer pool.Close()
+	return buildSchema(schema, pool, false)
+}
+
+// NewSchemaWithPool builds schema, submitting property and composed-
+// subschema construction tasks to pool. Every SchemaProxy it creates keeps a
+// reference to pool, so a later, lazy proxy.Schema() call for this schema's
+// children reuses the same pool rather than spinning up a new one. The
+// caller owns pool's lifecycle and must Close it once the document it
+// belongs to, including every schema lazily resolved from it, is done being
+// built.
+func NewSchemaWithPool(schema *base.Schema, pool *SchemaBuildPool) *Schema {
+	return buildSchema(schema, pool, true)
+}
+
+// buildSchema does the actual work for NewSchemaWithConfig and
+// NewSchemaWithPool. pool is used to build schema's own properties and
+// composed subschemas concurrently either way; propagate controls whether
+// the SchemaProxy values wrapping them keep a reference to pool for their
+// own later, lazy resolution, which is only safe when the caller guarantees
+// pool stays open until then.
+func buildSchema(schema *base.Schema, pool *SchemaBuildPool, propagate bool) *Schema {
 	s := new(Schema)
 	s.low = schema
 	s.Title = schema.Title.Value
@@ -127,107 +169,80 @@ func NewSchema(schema *base.Schema) *Schema {
 	}
 	s.Enum = enum
 
-	// async work.
-	// any polymorphic properties need to be handled in their own threads
-	// any properties each need to be processed in their own thread.
-	// we go as fast as we can.
-
-	polyCompletedChan := make(chan bool)
-	propsChan := make(chan bool)
-	errChan := make(chan error)
-
-	// schema async
-	buildOutSchema := func(schemas []lowmodel.ValueReference[*base.SchemaProxy], items *[]*SchemaProxy,
-		doneChan chan bool, e chan error) {
-		bChan := make(chan *SchemaProxy)
-
-		// for every item, build schema async
-		buildSchemaChild := func(sch lowmodel.ValueReference[*base.SchemaProxy], bChan chan *SchemaProxy) {
-			p := &SchemaProxy{schema: &lowmodel.NodeReference[*base.SchemaProxy]{
-				ValueNode: sch.ValueNode,
-				Value:     sch.Value,
-			}}
-			bChan <- p
-		}
-		totalSchemas := len(schemas)
-		for v := range schemas {
-			go buildSchemaChild(schemas[v], bChan)
-		}
-		j := 0
-		for j < totalSchemas {
-			select {
-			case t := <-bChan:
-				j++
-				*items = append(*items, t)
-			}
-		}
-		doneChan <- true
-	}
-
-	// props async
-	plock := sync.RWMutex{}
-	var buildProps = func(k lowmodel.KeyReference[string], v lowmodel.ValueReference[*base.SchemaProxy], c chan bool,
-		props map[string]*SchemaProxy) {
-		defer plock.Unlock()
-		plock.Lock()
-		props[k.Value] = &SchemaProxy{schema: &lowmodel.NodeReference[*base.SchemaProxy]{
-			Value:     v.Value,
-			KeyNode:   k.KeyNode,
-			ValueNode: v.ValueNode,
-		},
+	// Properties and composed subschemas are each cheap to wrap, but a spec
+	// can easily carry thousands of them, so the work is farmed out to pool
+	// rather than spawning a goroutine per item. Every composed-subschema
+	// result slot is pre-allocated by index before any task runs, and each
+	// property task writes only its own key into props under propsMu, so no
+	// task ever shares a mutable slot with another, and s.Properties is
+	// assigned exactly once, after every task has completed.
+	var wg sync.WaitGroup
+
+	// childPool is what gets stored on each SchemaProxy this call creates.
+	// It's only pool itself when the caller promised (by calling
+	// NewSchemaWithPool directly) to keep pool open for this schema's
+	// lazily-resolved descendants too; otherwise it's nil, so a later
+	// proxy.Schema() call falls back to NewSchema rather than submitting to
+	// a pool this call has already closed.
+	var childPool *SchemaBuildPool
+	if propagate {
+		childPool = pool
+	}
+
+	buildOutSchema := func(schemas []lowmodel.ValueReference[*base.SchemaProxy]) []*SchemaProxy {
+		items := make([]*SchemaProxy, len(schemas))
+		for i := range schemas {
+			i, sch := i, schemas[i]
+			wg.Add(1)
+			pool.submit(func() {
+				defer wg.Done()
+				items[i] = &SchemaProxy{pool: childPool, schema: &lowmodel.NodeReference[*base.SchemaProxy]{
+					ValueNode: sch.ValueNode,
+					Value:     sch.Value,
+				}}
+			})
 		}
-		s.Properties = props
-		c <- true
+		return items
 	}
 
-	props := make(map[string]*SchemaProxy)
+	props := make(map[string]*SchemaProxy, len(schema.Properties.Value))
+	var propsMu sync.Mutex
 	for k, v := range schema.Properties.Value {
-		go buildProps(k, v, propsChan, props)
+		k, v := k, v
+		wg.Add(1)
+		pool.submit(func() {
+			defer wg.Done()
+			proxy := &SchemaProxy{pool: childPool, schema: &lowmodel.NodeReference[*base.SchemaProxy]{
+				Value:     v.Value,
+				KeyNode:   k.KeyNode,
+				ValueNode: v.ValueNode,
+			}}
+			propsMu.Lock()
+			props[k.Value] = proxy
+			propsMu.Unlock()
+		})
 	}
 
-	var allOf []*SchemaProxy
-	var oneOf []*SchemaProxy
-	var anyOf []*SchemaProxy
-	var not []*SchemaProxy
-	var items []*SchemaProxy
-
+	var allOf, oneOf, anyOf, not, items []*SchemaProxy
 	if !schema.AllOf.IsEmpty() {
-		go buildOutSchema(schema.AllOf.Value, &allOf, polyCompletedChan, errChan)
+		allOf = buildOutSchema(schema.AllOf.Value)
 	}
 	if !schema.AnyOf.IsEmpty() {
-		go buildOutSchema(schema.AnyOf.Value, &anyOf, polyCompletedChan, errChan)
+		anyOf = buildOutSchema(schema.AnyOf.Value)
 	}
 	if !schema.OneOf.IsEmpty() {
-		go buildOutSchema(schema.OneOf.Value, &oneOf, polyCompletedChan, errChan)
+		oneOf = buildOutSchema(schema.OneOf.Value)
 	}
 	if !schema.Not.IsEmpty() {
-		go buildOutSchema(schema.Not.Value, &not, polyCompletedChan, errChan)
+		not = buildOutSchema(schema.Not.Value)
 	}
 	if !schema.Items.IsEmpty() {
-		go buildOutSchema(schema.Items.Value, &items, polyCompletedChan, errChan)
-	}
-
-	completeChildren := 0
-	completedProps := 0
-	totalProps := len(schema.Properties.Value)
-	totalChildren := len(schema.AllOf.Value) + len(schema.OneOf.Value) + len(schema.AnyOf.Value) + len(schema.Items.Value) + len(schema.Not.Value)
-	if totalProps+totalChildren > 0 {
-	allDone:
-		for true {
-			select {
-			case <-polyCompletedChan:
-				completeChildren++
-				if totalProps == completedProps && totalChildren == completeChildren {
-					break allDone
-				}
-			case <-propsChan:
-				completedProps++
-				if totalProps == completedProps && totalChildren == completeChildren {
-					break allDone
-				}
-			}
-		}
+		items = buildOutSchema(schema.Items.Value)
 	}
+
+	wg.Wait()
+
+	s.Properties = props
 	s.OneOf = oneOf
 	s.AnyOf = anyOf
 	s.AllOf = allOf
@@ -239,4 +254,4 @@ func NewSchema(schema *base.Schema) *Schema {
 
 func (s *Schema) GoLow() *base.Schema {
 	return s.low
-}
\ No newline at end of file
+}
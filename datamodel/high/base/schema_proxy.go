@@ -0,0 +1,64 @@
+// Copyright 2022 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package base
+
+import (
+	"sync"
+
+	lowmodel "github.com/pb33f/libopenapi/datamodel/low"
+	"github.com/pb33f/libopenapi/datamodel/low/base"
+)
+
+// SchemaProxy defers building a Schema until Schema is called, so a large
+// document doesn't eagerly build every schema it references. Once built,
+// the result is cached so repeated calls are free. Schema is safe to call
+// concurrently: buildOnce guards against two goroutines racing to build
+// (and cache) the same proxy.
+type SchemaProxy struct {
+	schema    *lowmodel.NodeReference[*base.SchemaProxy]
+	pool      *SchemaBuildPool
+	buildOnce sync.Once
+	built     *Schema
+}
+
+// newProxy wraps a Schema that was synthesized rather than parsed, so it has
+// no low-model node to defer to. Used by SchemaBuilder to let a Schema tree
+// be constructed entirely in memory.
+func newProxy(schema *Schema) *SchemaProxy {
+	return &SchemaProxy{built: schema}
+}
+
+// Schema resolves the proxy to the Schema it points at, building it from the
+// underlying low-model reference on first access, or returning the
+// synthesized Schema directly when the proxy was created by a SchemaBuilder.
+// When the proxy was created as part of a NewSchemaWithPool call, resolution
+// reuses that same pool instead of starting a new one, so the concurrency
+// ceiling for a document build applies to its lazily-resolved children too.
+// Concurrent callers resolving the same proxy all block on the single build
+// and observe the same cached result, rather than racing to build it twice.
+func (sp *SchemaProxy) Schema() *Schema {
+	if sp.schema == nil {
+		return sp.built
+	}
+	if sp.schema.Value == nil {
+		return nil
+	}
+	sp.buildOnce.Do(func() {
+		if sp.pool != nil {
+			sp.built = NewSchemaWithPool(sp.schema.Value.Schema(), sp.pool)
+		} else {
+			sp.built = NewSchema(sp.schema.Value.Schema())
+		}
+	})
+	return sp.built
+}
+
+// GoLow returns the low-model SchemaProxy this proxy was built from, or nil
+// for a proxy created by a SchemaBuilder.
+func (sp *SchemaProxy) GoLow() *base.SchemaProxy {
+	if sp.schema == nil {
+		return nil
+	}
+	return sp.schema.Value
+}
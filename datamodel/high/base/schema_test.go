@@ -0,0 +1,153 @@
+// Copyright 2022 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package base
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	lowmodel "github.com/pb33f/libopenapi/datamodel/low"
+	lowbase "github.com/pb33f/libopenapi/datamodel/low/base"
+	"gopkg.in/yaml.v3"
+)
+
+// buildTestSchema parses yml into a low-model Schema and wraps it with
+// NewSchema, the same path a document build takes.
+func buildTestSchema(t *testing.T, yml string) *Schema {
+	t.Helper()
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte(yml), &node); err != nil {
+		t.Fatalf("failed to unmarshal test schema: %v", err)
+	}
+	var low lowbase.Schema
+	if err := lowmodel.BuildModel(node.Content[0], &low); err != nil {
+		t.Fatalf("failed to build low model: %v", err)
+	}
+	if err := low.Build(node.Content[0], nil); err != nil {
+		t.Fatalf("failed to build low schema: %v", err)
+	}
+	return NewSchema(&low)
+}
+
+func manyPropsYAML(n int) string {
+	var sb strings.Builder
+	sb.WriteString("type: object\nproperties:\n")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&sb, "  prop%d:\n    type: string\n", i)
+	}
+	return sb.String()
+}
+
+// TestNewSchema_ConcurrentProperties builds a schema with hundreds of
+// properties and must be run with -race: every
+// property slot is owned by a single pool task and s.Properties is only
+// assigned after every task has completed, so there should be nothing for
+// the race detector to find.
+func TestNewSchema_ConcurrentProperties(t *testing.T) {
+	const propCount = 500
+	schema := buildTestSchema(t, manyPropsYAML(propCount))
+
+	if len(schema.Properties) != propCount {
+		t.Fatalf("expected %d properties, got %d", propCount, len(schema.Properties))
+	}
+	for i := 0; i < propCount; i++ {
+		name := fmt.Sprintf("prop%d", i)
+		if _, ok := schema.Properties[name]; !ok {
+			t.Fatalf("missing property %q", name)
+		}
+	}
+}
+
+// TestNewSchema_LazyNestedResolution builds a schema with a nested object
+// property, so resolving it exercises a SchemaProxy.Schema() call made well
+// after the top-level NewSchema call (and the ephemeral pool it built with)
+// has already returned. This is the scenario NewSchema's doc comment
+// promises works: a proxy whose pool was already closed must fall back to
+// building its own, not submit to the closed one.
+func TestNewSchema_LazyNestedResolution(t *testing.T) {
+	schema := buildTestSchema(t, `
+type: object
+properties:
+  child:
+    type: object
+    properties:
+      name:
+        type: string
+`)
+
+	proxy, ok := schema.Properties["child"]
+	if !ok {
+		t.Fatalf("missing property %q", "child")
+	}
+
+	child := proxy.Schema()
+	if child == nil {
+		t.Fatal("lazy resolution of child returned nil")
+	}
+	if len(child.Type) != 1 || child.Type[0] != "object" {
+		t.Fatalf("expected child.Type [object], got %v", child.Type)
+	}
+	if _, ok := child.Properties["name"]; !ok {
+		t.Fatalf("missing nested property %q", "name")
+	}
+}
+
+// TestSchemaProxy_ConcurrentSchema resolves the same proxy from many
+// goroutines at once, after the schema it belongs to was already built, and
+// must be run with -race: every caller must observe the identical *Schema,
+// not a torn read of sp.built or a schema built more than once.
+func TestSchemaProxy_ConcurrentSchema(t *testing.T) {
+	schema := buildTestSchema(t, `
+type: object
+properties:
+  child:
+    type: object
+`)
+
+	proxy, ok := schema.Properties["child"]
+	if !ok {
+		t.Fatalf("missing property %q", "child")
+	}
+
+	const callers = 64
+	results := make([]*Schema, callers)
+	var wg sync.WaitGroup
+	wg.Add(callers)
+	for i := 0; i < callers; i++ {
+		i := i
+		go func() {
+			defer wg.Done()
+			results[i] = proxy.Schema()
+		}()
+	}
+	wg.Wait()
+
+	for i := 1; i < callers; i++ {
+		if results[i] != results[0] {
+			t.Fatalf("concurrent Schema() calls returned different instances: %p vs %p", results[i], results[0])
+		}
+	}
+}
+
+func BenchmarkNewSchema_ManyProperties(b *testing.B) {
+	yml := manyPropsYAML(2000)
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte(yml), &node); err != nil {
+		b.Fatalf("failed to unmarshal benchmark schema: %v", err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var low lowbase.Schema
+		if err := lowmodel.BuildModel(node.Content[0], &low); err != nil {
+			b.Fatalf("failed to build low model: %v", err)
+		}
+		if err := low.Build(node.Content[0], nil); err != nil {
+			b.Fatalf("failed to build low schema: %v", err)
+		}
+		_ = NewSchema(&low)
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright 2022 Princess B33f Heavy Industries / Dave Shanley
+// SPDX-License-Identifier: MIT
+
+package base
+
+import "fmt"
+
+// ResolvedType is the semantic classification of a Schema: the one place
+// that answers "what kind of thing is this" instead of every consumer
+// re-deriving it from the loose Type []string plus the AllOf/OneOf/AnyOf
+// composition fields.
+type ResolvedType struct {
+	IsNullable              bool
+	IsAnonymous             bool
+	IsComplexObject         bool
+	IsArray                 bool
+	IsMap                   bool
+	IsTuple                 bool
+	IsPrimitive             bool
+	IsEnum                  bool
+	HasAdditionalItems      bool
+	HasAdditionalProperties bool
+	IsPolymorphic           bool
+	GoType                  string
+	Format                  string
+	ElementType             *ResolvedType
+	Discriminator           *Discriminator
+}
+
+// ResolveSchema classifies s, the way a property typed as s would need to be
+// handled by a generator, validator or linter. isRequired controls nullability
+// for schemas that don't declare nullable/null explicitly: an optional
+// property with no default is treated as nullable, matching how OpenAPI
+// tooling has always inferred "this may be absent" for untyped optional
+// fields.
+//
+// $ref is followed lazily through SchemaProxy.Schema(), so ResolveSchema
+// never forces resolution of a reference it doesn't need to inspect.
+func ResolveSchema(s *Schema, isRequired bool) (*ResolvedType, error) {
+	return resolveSchema(s, isRequired, make(map[*Schema]bool))
+}
+
+// resolveSchema is ResolveSchema's recursive core. seen tracks every schema
+// currently being resolved on the path from the root, so a self-referential
+// schema (an array whose element type is itself, or a map whose value type
+// is itself — both common for tree/linked-list shapes) stops recursing
+// instead of overflowing the stack: once a schema is seen again, it's
+// classified shallowly, by name, rather than expanded a second time.
+func resolveSchema(s *Schema, isRequired bool, seen map[*Schema]bool) (*ResolvedType, error) {
+	if s == nil {
+		return nil, fmt.Errorf("cannot resolve a nil schema")
+	}
+
+	rt := &ResolvedType{
+		Format:                  s.Format,
+		IsNullable:              isNullable(s, isRequired),
+		IsAnonymous:             s.Title == "",
+		IsEnum:                  len(s.Enum) > 0,
+		HasAdditionalProperties: s.AdditionalProperties != nil,
+		IsPolymorphic:           len(s.AllOf) > 0 || len(s.OneOf) > 0 || len(s.AnyOf) > 0,
+		Discriminator:           s.Discriminator,
+	}
+
+	if seen[s] {
+		switch {
+		case hasType(s, "array"):
+			rt.IsArray = true
+			rt.GoType = "[]" + goTypeName(s)
+		case hasType(s, "object"):
+			if len(s.Properties) > 0 {
+				rt.IsComplexObject = true
+			} else {
+				rt.IsMap = true
+			}
+			rt.GoType = goTypeName(s)
+		case rt.IsEnum:
+			rt.GoType = goTypeName(s)
+		default:
+			rt.IsPrimitive = true
+			rt.GoType = primitiveGoType(s)
+		}
+		return rt, nil
+	}
+	seen[s] = true
+	defer delete(seen, s)
+
+	switch {
+	case hasType(s, "array"):
+		rt.IsArray = true
+		rt.IsTuple = len(s.Items) > 1
+		if !rt.IsTuple && len(s.Items) == 1 {
+			elem := s.Items[0].Schema()
+			if elem != nil {
+				elemType, err := resolveSchema(elem, true, seen)
+				if err != nil {
+					return nil, err
+				}
+				rt.ElementType = elemType
+				rt.GoType = "[]" + elemType.GoType
+			}
+		}
+		if rt.GoType == "" {
+			rt.GoType = "[]any"
+		}
+
+	case hasType(s, "object"):
+		if len(s.Properties) > 0 {
+			rt.IsComplexObject = true
+			rt.GoType = goTypeName(s)
+		} else if valueSchema, ok := s.AdditionalProperties.(*Schema); ok {
+			rt.IsMap = true
+			elemType, err := resolveSchema(valueSchema, true, seen)
+			if err != nil {
+				return nil, err
+			}
+			rt.ElementType = elemType
+			rt.GoType = "map[string]" + elemType.GoType
+		} else {
+			rt.IsMap = true
+			rt.GoType = "map[string]any"
+		}
+
+	case rt.IsEnum:
+		rt.GoType = goTypeName(s)
+
+	default:
+		rt.IsPrimitive = true
+		rt.GoType = primitiveGoType(s)
+	}
+
+	return rt, nil
+}
+
+// isNullable honors the 3.0/3.1 split: 3.0 specs signal nullability with the
+// Nullable bool, while 3.1 specs fold "null" into the Type union instead.
+// A schema that isn't required and declares no default is also treated as
+// nullable, since the property may legitimately be absent.
+func isNullable(s *Schema, isRequired bool) bool {
+	if s.Nullable {
+		return true
+	}
+	if hasType(s, "null") {
+		return true
+	}
+	return !isRequired && s.Default == nil
+}
+
+// goTypeName derives the Go type name a generator would give s, falling back
+// to "any" for anonymous schemas that have no title to derive a name from.
+func goTypeName(s *Schema) string {
+	if s.Title == "" {
+		return "any"
+	}
+	return exportedTitle(s.Title)
+}
+
+func primitiveGoType(s *Schema) string {
+	switch {
+	case hasType(s, "string") && s.Format == "date-time":
+		return "time.Time"
+	case hasType(s, "string") && s.Format == "byte":
+		return "[]byte"
+	case hasType(s, "string"):
+		return "string"
+	case hasType(s, "integer") && s.Format == "int32":
+		return "int32"
+	case hasType(s, "integer"):
+		return "int64"
+	case hasType(s, "number") && s.Format == "float":
+		return "float32"
+	case hasType(s, "number"):
+		return "float64"
+	case hasType(s, "boolean"):
+		return "bool"
+	default:
+		return "any"
+	}
+}